@@ -0,0 +1,144 @@
+package genny
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Fingerprinter lets a Transformer opt into cacheability. Transformers
+// that don't implement it are fingerprinted by their type name, which is
+// correct for stateless transformers but will collide if two instances
+// of the same type behave differently (e.g. GoImports with different
+// LocalPrefix values) — such Transformers should implement Fingerprinter
+// themselves.
+type Fingerprinter interface {
+	Fingerprint() string
+}
+
+// CacheKeyer lets a File contribute extra state to its own cache key,
+// beyond its raw contents. Most Files don't need this — their byte
+// stream is the whole story — but a File that carries state which isn't
+// yet reflected in its bytes (e.g. gogen.File's ImportSet, which is only
+// rendered into the File's contents once ImportTransformer runs) must
+// fold that state in, or two Files with identical bytes-so-far but
+// different pending state will collide on the same Cache entry.
+type CacheKeyer interface {
+	CacheKey() string
+}
+
+// ContentCloner lets a File be given new bytes while preserving any
+// extra state it carries. TransformCached needs this: it reads a File's
+// bytes to compute a cache key, which consumes the File's reader, so it
+// must rebuild the File before transforming it — and a rebuild via
+// NewFileS alone would silently downgrade a typed wrapper like gogen.File
+// to a plain File, losing its ImportSet along with it.
+type ContentCloner interface {
+	WithContents(b []byte) File
+}
+
+// withContents returns f rebuilt with contents b, preserving f's
+// concrete type if it implements ContentCloner, and falling back to a
+// plain File otherwise.
+func withContents(f File, b []byte) File {
+	if cc, ok := f.(ContentCloner); ok {
+		return cc.WithContents(b)
+	}
+	return NewFileS(f.Name(), string(b))
+}
+
+// Cache stores the post-transform bytes for a File, keyed by a digest of
+// its destination path, pre-transform contents, and the ordered
+// Transformer chain that produced it. Runner consults it before running
+// the Transformer chain for a File, and populates it after.
+type Cache interface {
+	// Get returns the cached bytes for key, if present.
+	Get(key string) ([]byte, bool)
+	// Set stores value under key.
+	Set(key string, value []byte) error
+}
+
+// NopCache never stores or returns anything, so every File is always
+// transformed. It's the default for Runners that don't opt into caching.
+type NopCache struct{}
+
+// Get always reports a miss.
+func (NopCache) Get(key string) ([]byte, bool) { return nil, false }
+
+// Set is a no-op.
+func (NopCache) Set(key string, value []byte) error { return nil }
+
+// diskCache is a Cache backed by files in a directory, one per key.
+type diskCache struct {
+	dir string
+}
+
+// DiskCache returns a Cache that stores entries as files under dir, one
+// file per cache key. If dir is empty, it defaults to
+// $XDG_CACHE_HOME/genny (or $HOME/.cache/genny if XDG_CACHE_HOME is
+// unset).
+func DiskCache(dir string) Cache {
+	if dir == "" {
+		dir = defaultCacheDir()
+	}
+	return &diskCache{dir: dir}
+}
+
+func defaultCacheDir() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "genny")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".cache", "genny")
+}
+
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// Get implements Cache.
+func (c *diskCache) Get(key string) ([]byte, bool) {
+	b, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// Set implements Cache.
+func (c *diskCache) Set(key string, value []byte) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(ioutil.WriteFile(c.path(key), value, 0644))
+}
+
+// fingerprint returns t's Fingerprint() if it implements Fingerprinter,
+// otherwise its type name.
+func fingerprint(t Transformer) string {
+	if fp, ok := t.(Fingerprinter); ok {
+		return fp.Fingerprint()
+	}
+	return reflect.TypeOf(t).String()
+}
+
+// cacheKey hashes the tuple (destination path, pre-transform bytes,
+// ordered Transformer fingerprints, and f's CacheKey if it implements
+// CacheKeyer) with SHA-256, returning the hex digest used as a Cache key.
+func cacheKey(path string, src []byte, transformers []Transformer, f File) string {
+	h := sha256.New()
+	fmt.Fprintln(h, path)
+	h.Write(src)
+	for _, t := range transformers {
+		fmt.Fprintln(h, fingerprint(t))
+	}
+	if ck, ok := f.(CacheKeyer); ok {
+		fmt.Fprintln(h, ck.CacheKey())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,134 @@
+package genny
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingTransformer uppercases a File's contents and counts how many
+// times it actually ran, so tests can assert a cache hit skipped it.
+type countingTransformer struct {
+	calls *int
+}
+
+func (t countingTransformer) Transform(f File) (File, error) {
+	*t.calls++
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return f, err
+	}
+	return NewFileS(f.Name(), strings.ToUpper(string(b))), nil
+}
+
+func Test_NopCache_AlwaysMisses(t *testing.T) {
+	r := require.New(t)
+
+	c := NopCache{}
+	r.NoError(c.Set("key", []byte("value")))
+
+	_, ok := c.Get("key")
+	r.False(ok)
+}
+
+func Test_DiskCache_SetThenGet(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genny-cache")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	c := DiskCache(dir)
+	r.NoError(c.Set("key", []byte("value")))
+
+	got, ok := c.Get("key")
+	r.True(ok)
+	r.Equal("value", string(got))
+
+	_, ok = c.Get("missing")
+	r.False(ok)
+}
+
+// configuredTransformer is a stateful Transformer whose Fingerprint
+// includes its config, the way gogen.GoImports and gogen.ImportTransformer
+// do, so two differently-configured instances don't collide in a Cache.
+type configuredTransformer struct {
+	Prefix string
+}
+
+func (t configuredTransformer) Transform(f File) (File, error) { return f, nil }
+func (t configuredTransformer) Fingerprint() string            { return "configuredTransformer:" + t.Prefix }
+
+func Test_CacheKey_DiffersByFingerprint(t *testing.T) {
+	r := require.New(t)
+
+	f := NewFileS("foo.go", "src")
+	a := cacheKey("foo.go", []byte("src"), []Transformer{configuredTransformer{Prefix: "a"}}, f)
+	b := cacheKey("foo.go", []byte("src"), []Transformer{configuredTransformer{Prefix: "b"}}, f)
+	r.NotEqual(a, b)
+}
+
+func Test_CacheKey_SameFingerprint_SameKey(t *testing.T) {
+	r := require.New(t)
+
+	f := NewFileS("foo.go", "src")
+	a := cacheKey("foo.go", []byte("src"), []Transformer{configuredTransformer{Prefix: "a"}}, f)
+	b := cacheKey("foo.go", []byte("src"), []Transformer{configuredTransformer{Prefix: "a"}}, f)
+	r.Equal(a, b)
+}
+
+// cacheKeyerFile is a minimal CacheKeyer File double, standing in for
+// gogen.File, so cacheKey's own collision-avoidance can be tested without
+// importing gogen (which imports this package).
+type cacheKeyerFile struct {
+	File
+	key string
+}
+
+func (f cacheKeyerFile) CacheKey() string { return f.key }
+
+func Test_CacheKey_DiffersByCacheKeyer(t *testing.T) {
+	r := require.New(t)
+
+	a := cacheKeyerFile{File: NewFileS("foo.go", "src"), key: "imports:fmt"}
+	b := cacheKeyerFile{File: NewFileS("foo.go", "src"), key: "imports:fmt,os"}
+
+	ka := cacheKey("foo.go", []byte("src"), nil, a)
+	kb := cacheKey("foo.go", []byte("src"), nil, b)
+	r.NotEqual(ka, kb)
+}
+
+func Test_Runner_Cache_SkipsTransformOnHit(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genny-cache")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	calls := 0
+	newGenerator := func() *Generator {
+		g := New()
+		g.Transformer(countingTransformer{calls: &calls})
+		g.File(NewFileS("foo.txt", "hello"))
+		return g
+	}
+
+	run1 := DryRunner(context.Background())
+	run1.Cache = DiskCache(dir)
+	run1.With(newGenerator())
+	r.NoError(run1.Run())
+	r.Equal(1, calls)
+	r.Equal("HELLO", run1.Results().Files[0].String())
+
+	run2 := DryRunner(context.Background())
+	run2.Cache = DiskCache(dir)
+	run2.With(newGenerator())
+	r.NoError(run2.Run())
+	r.Equal(1, calls, "second run should hit the cache and skip the Transformer")
+	r.Equal("HELLO", run2.Results().Files[0].String())
+}
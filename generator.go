@@ -2,6 +2,7 @@ package genny
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os/exec"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ type Generator struct {
 	StepName     string
 	Should       func(*Runner) bool
 	Root         string
+	TeardownFn   func(*Runner) error
 	runners      []RunFn
 	transformers []Transformer
 	moot         *sync.RWMutex
@@ -52,6 +54,51 @@ func (g *Generator) Transform(f File) (File, error) {
 	return f, nil
 }
 
+// TransformCached behaves like Transform, but consults c first: if a
+// cache hit is found for f's destination path, pre-transform contents,
+// the fingerprints of this Generator's Transformer chain, and (if f
+// implements CacheKeyer) f's own CacheKey, the cached bytes are
+// substituted and the chain is skipped entirely. On a miss, Transform
+// runs as usual and its result is stored in c for next time.
+//
+// Reading f's bytes to compute the cache key consumes f's reader, so f
+// is rebuilt via withContents before either path uses it again — that
+// preserves f's concrete type (e.g. gogen.File and its ImportSet)
+// instead of silently downgrading it to a plain File.
+func (g *Generator) TransformCached(f File, c Cache) (File, error) {
+	g.moot.RLock()
+	transformers := g.transformers
+	g.moot.RUnlock()
+
+	src, err := ioutil.ReadAll(f)
+	if err != nil {
+		return f, errors.WithStack(err)
+	}
+	f = withContents(f, src)
+
+	key := cacheKey(f.Name(), src, transformers, f)
+	if cached, ok := c.Get(key); ok {
+		return withContents(f, cached), nil
+	}
+
+	out, err := g.Transform(f)
+	if err != nil {
+		return out, err
+	}
+
+	outBytes, err := ioutil.ReadAll(out)
+	if err != nil {
+		return out, errors.WithStack(err)
+	}
+	out = withContents(out, outBytes)
+
+	if err := c.Set(key, outBytes); err != nil {
+		return out, errors.WithStack(err)
+	}
+
+	return out, nil
+}
+
 // Transformer adds a file transform to the generator
 func (g *Generator) Transformer(t Transformer) {
 	g.moot.Lock()
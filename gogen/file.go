@@ -0,0 +1,88 @@
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/gobuffalo/genny"
+	"github.com/pkg/errors"
+)
+
+// File wraps a genny.File with an ImportSet, so templates can register
+// Go imports symbolically via the `import`/`importAs` helpers as they
+// execute instead of hand-managing an import block per file. Pair it
+// with ImportTransformer to materialize the registered imports into a
+// real `import (...)` block at write time.
+type File struct {
+	genny.File
+	Imports *ImportSet
+}
+
+// NewFile wraps name/r as a File with a fresh, empty ImportSet.
+func NewFile(name string, r io.Reader) *File {
+	return &File{
+		File:    genny.NewFile(name, r),
+		Imports: NewImportSet(),
+	}
+}
+
+// NewFileS is the string-contents equivalent of NewFile.
+func NewFileS(name, contents string) *File {
+	return &File{
+		File:    genny.NewFileS(name, contents),
+		Imports: NewImportSet(),
+	}
+}
+
+// Execute parses templateText and runs it against data, with the
+// `import`/`importAs` helpers bound to f.Imports merged into funcs, then
+// replaces f's contents with the result.
+func (f *File) Execute(templateText string, data interface{}, funcs template.FuncMap) error {
+	all := template.FuncMap{}
+	for k, v := range f.Imports.TemplateFuncs() {
+		all[k] = v
+	}
+	for k, v := range funcs {
+		all[k] = v
+	}
+
+	t, err := template.New(f.Name()).Funcs(all).Parse(templateText)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	bb := &bytes.Buffer{}
+	if err := t.Execute(bb, data); err != nil {
+		return errors.WithStack(err)
+	}
+
+	f.File = genny.NewFileS(f.Name(), bb.String())
+	return nil
+}
+
+// WithContents implements genny.ContentCloner: it returns a File with its
+// contents replaced by b but carrying the same ImportSet, so callers that
+// must read a File's bytes more than once — genny.Generator.TransformCached
+// does, to compute a cache key — don't lose track of registered imports
+// along the way.
+func (f *File) WithContents(b []byte) genny.File {
+	return &File{
+		File:    genny.NewFileS(f.Name(), string(b)),
+		Imports: f.Imports,
+	}
+}
+
+// CacheKey implements genny.CacheKeyer: it folds the ImportSet's
+// registered imports into the content address, so two Files with
+// identical contents-so-far but different pending imports don't collide
+// in a genny.Cache — the import block ImportTransformer materializes
+// differs even though the bytes TransformCached hashes do not.
+func (f *File) CacheKey() string {
+	bb := &bytes.Buffer{}
+	for _, imp := range f.Imports.Imports() {
+		fmt.Fprintf(bb, "%s %s\n", imp.Path, imp.Alias)
+	}
+	return bb.String()
+}
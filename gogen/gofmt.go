@@ -0,0 +1,60 @@
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"strings"
+
+	"github.com/gobuffalo/genny"
+	"github.com/pkg/errors"
+)
+
+// GoFmt is a genny.Transformer that runs the contents of any *.go File
+// through go/format.Source before it reaches the Runner's disk write.
+// Non-Go files are passed through untouched.
+type GoFmt struct{}
+
+// Transform implements genny.Transformer.
+func (t GoFmt) Transform(f genny.File) (genny.File, error) {
+	if !strings.HasSuffix(f.Name(), ".go") {
+		return f, nil
+	}
+
+	bb := &bytes.Buffer{}
+	if _, err := bb.ReadFrom(f); err != nil {
+		return f, errors.WithStack(err)
+	}
+	src := bb.Bytes()
+
+	out, err := format.Source(src)
+	if err != nil {
+		return f, annotateSourceErr(f.Name(), src, err)
+	}
+
+	return genny.NewFileS(f.Name(), string(out)), nil
+}
+
+// annotateSourceErr wraps a gofmt/goimports error with the offending source,
+// numbered by line, so template authors can find the bad line without
+// reproducing the generator run.
+func annotateSourceErr(name string, src []byte, err error) error {
+	lines := strings.Split(string(src), "\n")
+
+	numbered := &bytes.Buffer{}
+	for i, l := range lines {
+		fmt.Fprintf(numbered, "%4d| %s\n", i+1, l)
+	}
+
+	msg := err.Error()
+	if list, ok := err.(scanner.ErrorList); ok {
+		msgs := make([]string, len(list))
+		for i, e := range list {
+			msgs[i] = e.Error()
+		}
+		msg = strings.Join(msgs, "\n")
+	}
+
+	return errors.Wrapf(err, "%s: %s\n%s", name, msg, numbered.String())
+}
@@ -0,0 +1,40 @@
+package gogen
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/genny"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GoFmt_Transform_FormatsGoFile(t *testing.T) {
+	r := require.New(t)
+
+	in := genny.NewFileS("foo.go", "package foo\nfunc  Foo( )   {}\n")
+
+	out, err := GoFmt{}.Transform(in)
+	r.NoError(err)
+	r.Equal("foo.go", out.Name())
+	r.Equal("package foo\n\nfunc Foo() {}\n", out.String())
+}
+
+func Test_GoFmt_Transform_NonGoFile_PassesThrough(t *testing.T) {
+	r := require.New(t)
+
+	in := genny.NewFileS("foo.txt", "not   go")
+
+	out, err := GoFmt{}.Transform(in)
+	r.NoError(err)
+	r.Equal("not   go", out.String())
+}
+
+func Test_GoFmt_Transform_SyntaxError_AnnotatesSource(t *testing.T) {
+	r := require.New(t)
+
+	in := genny.NewFileS("foo.go", "package foo\nfunc {{\n")
+
+	_, err := GoFmt{}.Transform(in)
+	r.Error(err)
+	r.Contains(err.Error(), "foo.go")
+	r.Contains(err.Error(), "1| package foo")
+}
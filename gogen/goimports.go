@@ -0,0 +1,52 @@
+package gogen
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gobuffalo/genny"
+	"github.com/pkg/errors"
+	"golang.org/x/tools/imports"
+)
+
+// GoImports is a genny.Transformer that rewrites a *.go File's contents
+// to add missing imports and drop unused ones, the same way
+// `goimports -w` would. Non-Go files are passed through untouched.
+type GoImports struct {
+	// LocalPrefix is passed straight through to imports.Options.LocalPrefix,
+	// allowing callers to group their own module's packages apart from
+	// third-party ones.
+	LocalPrefix string
+}
+
+// Transform implements genny.Transformer.
+func (t GoImports) Transform(f genny.File) (genny.File, error) {
+	if !strings.HasSuffix(f.Name(), ".go") {
+		return f, nil
+	}
+
+	bb := &bytes.Buffer{}
+	if _, err := bb.ReadFrom(f); err != nil {
+		return f, errors.WithStack(err)
+	}
+	src := bb.Bytes()
+
+	out, err := imports.Process(f.Name(), src, &imports.Options{
+		Comments:    true,
+		TabIndent:   true,
+		TabWidth:    8,
+		LocalPrefix: t.LocalPrefix,
+	})
+	if err != nil {
+		return f, annotateSourceErr(f.Name(), src, err)
+	}
+
+	return genny.NewFileS(f.Name(), string(out)), nil
+}
+
+// Fingerprint implements genny.Fingerprinter, incorporating LocalPrefix so
+// that a genny.Cache doesn't conflate two differently-configured
+// GoImports instances.
+func (t GoImports) Fingerprint() string {
+	return "gogen.GoImports:" + t.LocalPrefix
+}
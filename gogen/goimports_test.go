@@ -0,0 +1,46 @@
+package gogen
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/genny"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GoImports_Transform_AddsMissingImport(t *testing.T) {
+	r := require.New(t)
+
+	in := genny.NewFileS("foo.go", "package foo\n\nfunc Foo() {\n\tfmt.Println(\"hi\")\n}\n")
+
+	out, err := GoImports{}.Transform(in)
+	r.NoError(err)
+	r.Contains(out.String(), `"fmt"`)
+}
+
+func Test_GoImports_Transform_RemovesUnusedImport(t *testing.T) {
+	r := require.New(t)
+
+	in := genny.NewFileS("foo.go", "package foo\n\nimport \"fmt\"\n\nfunc Foo() {}\n")
+
+	out, err := GoImports{}.Transform(in)
+	r.NoError(err)
+	r.NotContains(out.String(), `"fmt"`)
+}
+
+func Test_GoImports_Transform_NonGoFile_PassesThrough(t *testing.T) {
+	r := require.New(t)
+
+	in := genny.NewFileS("foo.txt", "fmt.Println")
+
+	out, err := GoImports{}.Transform(in)
+	r.NoError(err)
+	r.Equal("fmt.Println", out.String())
+}
+
+func Test_GoImports_Fingerprint_IncludesLocalPrefix(t *testing.T) {
+	r := require.New(t)
+
+	a := GoImports{LocalPrefix: "github.com/acme"}.Fingerprint()
+	b := GoImports{LocalPrefix: "github.com/other"}.Fingerprint()
+	r.NotEqual(a, b)
+}
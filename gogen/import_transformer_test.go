@@ -0,0 +1,156 @@
+package gogen
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/gobuffalo/genny"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ImportTransformer_Transform_Sentinel(t *testing.T) {
+	r := require.New(t)
+
+	f := NewFileS("foo.go", "package foo\n\n// GENNY:IMPORTS\n\nfunc Foo() {}\n")
+	f.Imports.Add("fmt")
+	f.Imports.Add("github.com/foo/bar")
+
+	out, err := ImportTransformer{}.Transform(f)
+	r.NoError(err)
+
+	s := out.String()
+	r.Contains(s, "import (")
+	r.Contains(s, `"fmt"`)
+	r.Contains(s, `"github.com/foo/bar"`)
+	r.NotContains(s, "GENNY:IMPORTS")
+}
+
+func Test_ImportTransformer_Transform_GroupsLocalPrefix(t *testing.T) {
+	r := require.New(t)
+
+	f := NewFileS("foo.go", "package foo\n\nimport ()\n")
+	f.Imports.Add("fmt")
+	f.Imports.Add("github.com/other/pkg")
+	f.Imports.Add("github.com/acme/widgets")
+
+	out, err := ImportTransformer{LocalPrefix: "github.com/acme"}.Transform(f)
+	r.NoError(err)
+
+	s := out.String()
+	fmtIdx := indexOf(s, `"fmt"`)
+	otherIdx := indexOf(s, `"github.com/other/pkg"`)
+	localIdx := indexOf(s, `"github.com/acme/widgets"`)
+
+	r.True(fmtIdx < otherIdx)
+	r.True(otherIdx < localIdx)
+}
+
+func Test_ImportTransformer_Transform_NoMarker(t *testing.T) {
+	r := require.New(t)
+
+	f := NewFileS("foo.go", "package foo\n")
+	f.Imports.Add("fmt")
+
+	_, err := ImportTransformer{}.Transform(f)
+	r.Error(err)
+}
+
+func Test_ImportTransformer_Transform_NoImports_PassesThrough(t *testing.T) {
+	r := require.New(t)
+
+	f := NewFileS("foo.go", "package foo\n\n// GENNY:IMPORTS\n")
+
+	out, err := ImportTransformer{}.Transform(f)
+	r.NoError(err)
+	r.Equal(f.String(), out.String())
+}
+
+func Test_ImportTransformer_Transform_NonFile_PassesThrough(t *testing.T) {
+	r := require.New(t)
+
+	in := genny.NewFileS("foo.go", "package foo\n")
+
+	out, err := ImportTransformer{}.Transform(in)
+	r.NoError(err)
+	r.Equal(in, out)
+}
+
+func Test_ImportTransformer_Fingerprint_IncludesLocalPrefix(t *testing.T) {
+	r := require.New(t)
+
+	a := ImportTransformer{LocalPrefix: "github.com/acme"}.Fingerprint()
+	b := ImportTransformer{LocalPrefix: "github.com/other"}.Fingerprint()
+	r.NotEqual(a, b)
+}
+
+// Test_ImportTransformer_ThroughRunner drives a *File through a real
+// genny.Runner, the way a generator actually uses ImportTransformer,
+// instead of calling Transform directly. TransformCached (consulted by
+// Runner.File on every run, cache or not) used to rebuild Files via
+// genny.NewFileS, which downgraded a *File to a plain genny.File and
+// silently dropped its ImportSet before ImportTransformer ever saw it.
+func Test_ImportTransformer_ThroughRunner(t *testing.T) {
+	r := require.New(t)
+
+	f := NewFileS("foo.go", "package foo\n\n// GENNY:IMPORTS\n\nfunc Foo() {}\n")
+	f.Imports.Add("fmt")
+
+	g := genny.New()
+	g.Transformer(ImportTransformer{})
+	g.File(f)
+
+	run := genny.DryRunner(context.Background())
+	run.With(g)
+	r.NoError(run.Run())
+
+	out := run.Results().Files[0].String()
+	r.Contains(out, "import (")
+	r.Contains(out, `"fmt"`)
+	r.NotContains(out, "GENNY:IMPORTS")
+}
+
+// Test_ImportTransformer_ThroughRunner_Cache exercises the same path with
+// a real Cache: two Files at the same path with the same contents-so-far
+// but different registered imports must not share a cache entry and
+// return each other's stale import block.
+func Test_ImportTransformer_ThroughRunner_Cache(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "gogen-cache")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	run := func(importPath string) string {
+		f := NewFileS("foo.go", "package foo\n\n// GENNY:IMPORTS\n\nfunc Foo() {}\n")
+		f.Imports.Add(importPath)
+
+		g := genny.New()
+		g.Transformer(ImportTransformer{})
+		g.File(f)
+
+		rr := genny.DryRunner(context.Background())
+		rr.Cache = genny.DiskCache(dir)
+		rr.With(g)
+		r.NoError(rr.Run())
+
+		return rr.Results().Files[0].String()
+	}
+
+	fmtOut := run("fmt")
+	osOut := run("os")
+
+	r.Contains(fmtOut, `"fmt"`)
+	r.Contains(osOut, `"os"`)
+	r.NotContains(osOut, `"fmt"`)
+}
+
+func indexOf(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i:i+len(substr)] == substr {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,110 @@
+package gogen
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// ImportSet tracks the Go imports a template needs as it executes,
+// keyed by import path, and resolves the local identifier each one
+// should be referred to by. It's meant to be shared across a single
+// File's template execution and consumed afterwards by ImportTransformer,
+// which materializes it into a real `import (...)` block.
+type ImportSet struct {
+	mu      sync.Mutex
+	aliases map[string]string // import path -> local identifier
+	taken   map[string]bool   // local identifier -> in use
+}
+
+// NewImportSet returns an empty, ready-to-use ImportSet.
+func NewImportSet() *ImportSet {
+	return &ImportSet{
+		aliases: map[string]string{},
+		taken:   map[string]bool{},
+	}
+}
+
+// Add registers path, deriving its local identifier from the last
+// element of the import path (auto-aliasing on collision), and returns
+// that identifier.
+func (is *ImportSet) Add(importPath string) string {
+	return is.AddAs(importPath, path.Base(importPath))
+}
+
+// AddAs registers path under the given alias, adjusting the alias if
+// it's already taken by a different import path, and returns the
+// identifier callers should use to refer to the package.
+func (is *ImportSet) AddAs(importPath, alias string) string {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	if existing, ok := is.aliases[importPath]; ok {
+		return existing
+	}
+
+	id := alias
+	for n := 2; is.taken[id]; n++ {
+		id = alias + strconv.Itoa(n)
+	}
+
+	is.aliases[importPath] = id
+	is.taken[id] = true
+	return id
+}
+
+// Imports returns the registered import paths and their local
+// identifiers, sorted by import path.
+func (is *ImportSet) Imports() []Import {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	out := make([]Import, 0, len(is.aliases))
+	for p, alias := range is.aliases {
+		out = append(out, Import{Path: p, Alias: alias})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// Import is a single entry in an ImportSet.
+type Import struct {
+	Path  string
+	Alias string
+}
+
+// String renders the import as it should appear inside an import block,
+// omitting the alias when it matches the package's conventional name.
+func (i Import) String() string {
+	if i.Alias == path.Base(i.Path) {
+		return strconv.Quote(i.Path)
+	}
+	return i.Alias + " " + strconv.Quote(i.Path)
+}
+
+// TemplateFuncs returns the `import` and `importAs` template helpers
+// bound to this ImportSet, for use as template.FuncMap entries when
+// executing a File's template.
+func (is *ImportSet) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"import": func(importPath string) string {
+			return is.Add(importPath)
+		},
+		"importAs": func(importPath, alias string) string {
+			return is.AddAs(importPath, alias)
+		},
+	}
+}
+
+// isStdlib reports whether importPath looks like a standard library
+// import, i.e. it has no dot in its first path element.
+func isStdlib(importPath string) bool {
+	first := importPath
+	if i := strings.Index(importPath, "/"); i >= 0 {
+		first = importPath[:i]
+	}
+	return !strings.Contains(first, ".")
+}
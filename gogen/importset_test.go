@@ -0,0 +1,79 @@
+package gogen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ImportSet_Add(t *testing.T) {
+	r := require.New(t)
+
+	is := NewImportSet()
+	alias := is.Add("github.com/foo/bar")
+	r.Equal("bar", alias)
+
+	// adding the same path again returns the same alias
+	r.Equal("bar", is.Add("github.com/foo/bar"))
+}
+
+func Test_ImportSet_Add_AliasesOnCollision(t *testing.T) {
+	r := require.New(t)
+
+	is := NewImportSet()
+	first := is.Add("github.com/foo/bar")
+	second := is.Add("github.com/baz/bar")
+
+	r.Equal("bar", first)
+	r.Equal("bar2", second)
+}
+
+func Test_ImportSet_AddAs(t *testing.T) {
+	r := require.New(t)
+
+	is := NewImportSet()
+	alias := is.AddAs("github.com/foo/bar", "fbar")
+	r.Equal("fbar", alias)
+	r.Equal("fbar", is.AddAs("github.com/foo/bar", "fbar"))
+}
+
+func Test_ImportSet_Imports_SortedByPath(t *testing.T) {
+	r := require.New(t)
+
+	is := NewImportSet()
+	is.Add("github.com/zzz/pkg")
+	is.Add("github.com/aaa/pkg")
+
+	imports := is.Imports()
+	r.Len(imports, 2)
+	r.Equal("github.com/aaa/pkg", imports[0].Path)
+	r.Equal("github.com/zzz/pkg", imports[1].Path)
+}
+
+func Test_Import_String(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal(`"github.com/foo/bar"`, Import{Path: "github.com/foo/bar", Alias: "bar"}.String())
+	r.Equal(`fbar "github.com/foo/bar"`, Import{Path: "github.com/foo/bar", Alias: "fbar"}.String())
+}
+
+func Test_ImportSet_TemplateFuncs(t *testing.T) {
+	r := require.New(t)
+
+	is := NewImportSet()
+	funcs := is.TemplateFuncs()
+
+	importFn := funcs["import"].(func(string) string)
+	importAsFn := funcs["importAs"].(func(string, string) string)
+
+	r.Equal("bar", importFn("github.com/foo/bar"))
+	r.Equal("fbar", importAsFn("github.com/foo/baz", "fbar"))
+}
+
+func Test_IsStdlib(t *testing.T) {
+	r := require.New(t)
+
+	r.True(isStdlib("fmt"))
+	r.True(isStdlib("net/http"))
+	r.False(isStdlib("github.com/foo/bar"))
+}
@@ -0,0 +1,104 @@
+package genny
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"github.com/pkg/errors"
+)
+
+// ProtocFeatures is the bitmask of optional protoc plugin features a
+// ProtocRunner advertises back to protoc in the CodeGeneratorResponse.
+const ProtocFeatures = uint64(plugin.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+
+// ProtocRunner returns a Runner suited to being driven by protoc as a
+// `protoc-gen-*` plugin. It reads a CodeGeneratorRequest from os.Stdin and
+// exposes it to Generators on Runner.ProtoRequest. Once Run has finished,
+// call WriteProtocResponse to serialize every collected File into a
+// CodeGeneratorResponse on os.Stdout.
+//
+// protoc owns stdin/stdout for the lifetime of the plugin invocation, so
+// Command is not supported under ProtocRunner and always errors.
+func ProtocRunner(ctx context.Context) *Runner {
+	r := NewRunner(ctx)
+	r.SupportedFeatures = ProtocFeatures
+
+	r.ExecFn = func(cmd *exec.Cmd) error {
+		return errors.New("genny: Command is not supported by ProtocRunner; protoc owns stdin/stdout")
+	}
+	r.WriteFn = func(f File) error {
+		return nil // files are written to the CodeGeneratorResponse, not the real disk
+	}
+
+	req := &plugin.CodeGeneratorRequest{}
+	b, err := ioutil.ReadAll(os.Stdin)
+	if err == nil {
+		err = proto.Unmarshal(b, req)
+	}
+	if err != nil {
+		r.protoReqErr = errors.WithStack(err)
+	}
+	r.ProtoRequest = req
+
+	return r
+}
+
+// WriteProtocResponse serializes the Files collected during Run into a
+// CodeGeneratorResponse and writes it to os.Stdout. It should be called
+// once, after Run has returned.
+func (r *Runner) WriteProtocResponse() error {
+	if r.protoReqErr != nil {
+		return r.protoReqErr
+	}
+
+	res := &plugin.CodeGeneratorResponse{
+		SupportedFeatures: &r.SupportedFeatures,
+	}
+
+	for _, f := range r.Results().Files {
+		name := f.Name()
+		if err := validateProtocOutputPath(name); err != nil {
+			return errors.WithStack(err)
+		}
+
+		content, err := ioutil.ReadAll(f)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		res.File = append(res.File, &plugin.CodeGeneratorResponse_File{
+			Name:    proto.String(name),
+			Content: proto.String(string(content)),
+		})
+	}
+
+	out, err := proto.Marshal(res)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = os.Stdout.Write(out)
+	return errors.WithStack(err)
+}
+
+// validateProtocOutputPath rejects paths a protoc plugin must never emit:
+// absolute paths and paths that escape the output directory via "..".
+func validateProtocOutputPath(name string) error {
+	if path.IsAbs(filepath.ToSlash(name)) {
+		return errors.Errorf("genny: protoc plugin output path %q must be relative", name)
+	}
+
+	clean := filepath.ToSlash(filepath.Clean(name))
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return errors.Errorf("genny: protoc plugin output path %q escapes the output directory", name)
+	}
+
+	return nil
+}
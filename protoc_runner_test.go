@@ -0,0 +1,55 @@
+package genny
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateProtocOutputPath(t *testing.T) {
+	table := []struct {
+		name string
+		path string
+		boom bool
+	}{
+		{"relative", "foo/bar.go", false},
+		{"relative with dot", "./foo/bar.go", false},
+		{"absolute unix", "/etc/passwd", true},
+		{"escapes via dotdot", "../../etc/passwd", true},
+		{"just dotdot", "..", true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(st *testing.T) {
+			r := require.New(st)
+
+			err := validateProtocOutputPath(tt.path)
+			if tt.boom {
+				r.Error(err)
+			} else {
+				r.NoError(err)
+			}
+		})
+	}
+}
+
+func Test_ProtocRunner_WriteFn_DoesNotTouchRealDisk(t *testing.T) {
+	r := require.New(t)
+
+	run := ProtocRunner(context.Background())
+
+	const path = "protoc_runner_test_should_not_exist.go"
+	r.NoError(run.WriteFn(NewFileS(path, "package foo")))
+
+	_, err := os.Stat(path)
+	r.True(os.IsNotExist(err))
+}
+
+func Test_ProtocRunner_Command_Errors(t *testing.T) {
+	r := require.New(t)
+
+	run := ProtocRunner(context.Background())
+	r.Error(run.ExecFn(nil))
+}
@@ -0,0 +1,313 @@
+package genny
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"github.com/pkg/errors"
+)
+
+// Results is the aggregate output of a Runner's Run: every File written,
+// Command executed, and http.Request made by the Generators it ran.
+type Results struct {
+	Files    []File
+	Commands []*exec.Cmd
+	Requests []Request
+}
+
+// Request pairs an outgoing http.Request with the http.Response (if any)
+// a Runner received for it.
+type Request struct {
+	Request  *http.Request
+	Response *http.Response
+}
+
+// Disk tracks the current File at every path a Runner knows about, keyed
+// by name, so later Generators can read Files an earlier one wrote and a
+// TeardownFn can drop a File a Generator decided not to keep. It holds
+// only Files passed to Add; Runner.FindFile falls back to the real
+// filesystem for anything else without adding it here.
+type Disk struct {
+	original sync.Map // name -> File
+}
+
+func newDisk() *Disk {
+	return &Disk{}
+}
+
+// Add stores f, replacing any existing File with the same name.
+func (d *Disk) Add(f File) {
+	d.original.Store(f.Name(), f)
+}
+
+// Delete removes the File at name, if any.
+func (d *Disk) Delete(name string) {
+	d.original.Delete(name)
+}
+
+// Find returns the File at name, if known.
+func (d *Disk) Find(name string) (File, bool) {
+	v, ok := d.original.Load(name)
+	if !ok {
+		return nil, false
+	}
+	return v.(File), true
+}
+
+// Files returns every known File, sorted by name.
+func (d *Disk) Files() []File {
+	var files []File
+	d.original.Range(func(_, v interface{}) bool {
+		files = append(files, v.(File))
+		return true
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	return files
+}
+
+// Runner drives one or more Generators, routing every File, Command, and
+// http.Request they produce through ExecFn/WriteFn/RequestFn so that
+// callers (DryRunner, WetRunner, and the task-specific runners alongside
+// this file) can swap in different side effects — or none at all —
+// without the Generators knowing the difference.
+type Runner struct {
+	Root string
+	Disk *Disk
+
+	ExecFn    func(*exec.Cmd) error
+	WriteFn   func(File) error
+	RequestFn func(*http.Request) (*http.Response, error)
+
+	// Cache is consulted before running the current Generator's
+	// Transformer chain on a File; see Cache and Generator.TransformCached.
+	// Defaults to NopCache{}, so every File is always transformed unless a
+	// caller opts into caching.
+	Cache Cache
+
+	ctx              context.Context
+	moot             *sync.RWMutex
+	generators       []*Generator
+	currentGenerator *Generator
+	commands         []*exec.Cmd
+	requests         []Request
+
+	// ProtoRequest is the CodeGeneratorRequest a ProtocRunner read from
+	// os.Stdin, exposed here so Generators can branch on the files and
+	// parameters protoc invoked them with. protoReqErr records a failure
+	// to read or unmarshal it; WriteProtocResponse surfaces it.
+	ProtoRequest *plugin.CodeGeneratorRequest
+	protoReqErr  error
+
+	// SupportedFeatures is advertised back to protoc by WriteProtocResponse.
+	SupportedFeatures uint64
+
+	// FinalizeFn, if set, runs once every Generator has completed
+	// successfully, and its error (if any) becomes Run's return value.
+	// VerifyRunner uses this to surface its aggregated mismatch error
+	// through the normal Run() return path instead of a side-channel the
+	// caller has to remember to check.
+	FinalizeFn func(*Runner) error
+
+	// verifyMismatches collects the paths (and diffs) a VerifyRunner found
+	// out of date; nil for every other kind of Runner. See VerifyErrors.
+	verifyMismatches *[]string
+}
+
+// NewRunner returns a bare Runner: every side effect is a no-op and
+// RequestFn reports a stub 200 response, so Generators can be exercised
+// safely before picking DryRunner, WetRunner, or a task-specific runner.
+func NewRunner(ctx context.Context) *Runner {
+	return &Runner{
+		ctx:   ctx,
+		Disk:  newDisk(),
+		Cache: NopCache{},
+		moot:  &sync.RWMutex{},
+		ExecFn: func(cmd *exec.Cmd) error {
+			return nil
+		},
+		WriteFn: func(f File) error {
+			return nil
+		},
+		RequestFn: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		},
+	}
+}
+
+// DryRunner returns a Runner that executes Generators and records every
+// File, Command, and http.Request they produce in Results, without ever
+// touching the real filesystem, shelling out, or hitting the network.
+func DryRunner(ctx context.Context) *Runner {
+	return NewRunner(ctx)
+}
+
+// WetRunner returns a Runner that, in addition to recording Results,
+// actually writes Files to disk under Root, runs Commands for real, and
+// performs http.Requests against the network.
+func WetRunner(ctx context.Context) *Runner {
+	r := NewRunner(ctx)
+
+	r.ExecFn = func(cmd *exec.Cmd) error {
+		return errors.WithStack(cmd.Run())
+	}
+
+	r.WriteFn = func(f File) error {
+		path := filepath.Join(r.Root, f.Name())
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errors.WithStack(err)
+		}
+
+		b, err := ioutil.ReadAll(f)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		return errors.WithStack(ioutil.WriteFile(path, b, 0644))
+	}
+
+	r.RequestFn = func(req *http.Request) (*http.Response, error) {
+		return http.DefaultClient.Do(req)
+	}
+
+	return r
+}
+
+// With adds g to the set of Generators Run will execute, in the order
+// added.
+func (r *Runner) With(g *Generator) {
+	r.moot.Lock()
+	defer r.moot.Unlock()
+	r.generators = append(r.generators, g)
+}
+
+// Run executes every added Generator's RunFns in order. A Generator whose
+// Should func returns false is skipped entirely. Whether or not a
+// Generator's RunFns succeed, its TeardownFn (if any) always runs before
+// Run moves on to the next Generator; a TeardownFn error takes precedence
+// over a RunFn error from the same Generator.
+func (r *Runner) Run() error {
+	for _, g := range r.generators {
+		if g.Should != nil && !g.Should(r) {
+			continue
+		}
+
+		r.moot.Lock()
+		r.currentGenerator = g
+		r.moot.Unlock()
+
+		runErr := runGenerator(r, g)
+
+		if g.TeardownFn != nil {
+			if err := g.TeardownFn(r); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		if runErr != nil {
+			return errors.WithStack(runErr)
+		}
+	}
+
+	if r.FinalizeFn != nil {
+		return errors.WithStack(r.FinalizeFn(r))
+	}
+
+	return nil
+}
+
+func runGenerator(r *Runner, g *Generator) error {
+	for _, fn := range g.runners {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exec records cmd and runs it through ExecFn.
+func (r *Runner) Exec(cmd *exec.Cmd) error {
+	r.moot.Lock()
+	r.commands = append(r.commands, cmd)
+	r.moot.Unlock()
+
+	return r.ExecFn(cmd)
+}
+
+// Request records req and performs it through RequestFn, returning an
+// error if the response's status code is 400 or above.
+func (r *Runner) Request(req *http.Request) (*http.Response, error) {
+	res, err := r.RequestFn(req)
+
+	r.moot.Lock()
+	r.requests = append(r.requests, Request{Request: req, Response: res})
+	r.moot.Unlock()
+
+	if err != nil {
+		return res, errors.WithStack(err)
+	}
+	if res != nil && res.StatusCode >= http.StatusBadRequest {
+		return res, errors.Errorf("genny: %s %s returned %d", req.Method, req.URL, res.StatusCode)
+	}
+
+	return res, nil
+}
+
+// File transforms f through the currently running Generator's Transformer
+// chain — consulting Cache first, so unchanged work can be skipped —
+// records it on Disk, and writes it through WriteFn.
+func (r *Runner) File(f File) error {
+	r.moot.RLock()
+	g := r.currentGenerator
+	c := r.Cache
+	r.moot.RUnlock()
+
+	if g != nil {
+		if c == nil {
+			c = NopCache{}
+		}
+		var err error
+		f, err = g.TransformCached(f, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := r.WriteFn(f); err != nil {
+		return err
+	}
+	r.Disk.Add(f)
+	return nil
+}
+
+// Results returns every File, Command, and http.Request collected so far.
+func (r *Runner) Results() *Results {
+	r.moot.RLock()
+	defer r.moot.RUnlock()
+	return &Results{
+		Files:    r.Disk.Files(),
+		Commands: r.commands,
+		Requests: r.requests,
+	}
+}
+
+// FindFile returns the File at name, preferring one already known to Disk
+// (written by an earlier Generator, or added to Disk directly) and
+// falling back to reading it from Root on the real filesystem.
+func (r *Runner) FindFile(name string) (File, error) {
+	if f, ok := r.Disk.Find(name); ok {
+		return f, nil
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(r.Root, name))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return NewFileS(name, string(b)), nil
+}
@@ -0,0 +1,222 @@
+package genny
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// VerifyRunner returns a Runner that behaves like WetRunner for the
+// purposes of executing Generators and collecting File results, but
+// never writes to disk. Instead, every generated File is diffed against
+// the file already on disk at the same path under Root — the same path
+// WetRunner would have written to; Commands are skipped entirely, the
+// same as DryRunner.
+//
+// Run itself returns the aggregated VerifyErrors, listing every path
+// whose generated contents differ from disk, each with a unified diff,
+// and every path missing from disk, so CI can fail a `genny verify` step on the same
+// run.Run() error it already checks, without a separate call to
+// VerifyErrors.
+func VerifyRunner(ctx context.Context) *Runner {
+	r := NewRunner(ctx)
+
+	r.ExecFn = func(cmd *exec.Cmd) error {
+		return nil
+	}
+	r.RequestFn = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}
+
+	var mismatches []string
+	r.WriteFn = func(f File) error {
+		name := f.Name()
+
+		want, err := ioutil.ReadAll(f)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		diskPath := filepath.Join(r.Root, name)
+		got, err := ioutil.ReadFile(diskPath)
+		if os.IsNotExist(err) {
+			mismatches = append(mismatches, fmt.Sprintf("%s: missing from disk", name))
+			return nil
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if !bytes.Equal(want, got) {
+			mismatches = append(mismatches, fmt.Sprintf("%s:\n%s", name, unifiedDiff(name, string(got), string(want))))
+		}
+
+		return nil
+	}
+	r.verifyMismatches = &mismatches
+	r.FinalizeFn = func(r *Runner) error {
+		return r.VerifyErrors()
+	}
+
+	return r
+}
+
+// diffContext is the number of unchanged lines of context kept around
+// each change in a unifiedDiff hunk, matching `diff -u`'s default.
+const diffContext = 3
+
+// diffLine is one line of a flattened, line-level diff between disk and
+// generated, tagged with the line number(s) it occupies in each side.
+type diffLine struct {
+	kind    byte // ' ', '-', or '+'
+	text    string
+	oldLine int // valid when kind is ' ' or '-'
+	newLine int // valid when kind is ' ' or '+'
+}
+
+// flattenDiffLines expands diffmatchpatch's line-grouped diffs into one
+// diffLine per line, numbering each side as it goes.
+func flattenDiffLines(diffs []diffmatchpatch.Diff) []diffLine {
+	var out []diffLine
+	oldLine, newLine := 1, 1
+	for _, d := range diffs {
+		if d.Text == "" {
+			continue
+		}
+
+		lines := strings.Split(d.Text, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+
+		for _, line := range lines {
+			switch d.Type {
+			case diffmatchpatch.DiffDelete:
+				out = append(out, diffLine{kind: '-', text: line, oldLine: oldLine})
+				oldLine++
+			case diffmatchpatch.DiffInsert:
+				out = append(out, diffLine{kind: '+', text: line, newLine: newLine})
+				newLine++
+			default:
+				out = append(out, diffLine{kind: ' ', text: line, oldLine: oldLine, newLine: newLine})
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	return out
+}
+
+// unifiedHunks groups dl's changed lines into [start, end) ranges, each
+// padded with up to diffContext lines of surrounding context, merging
+// changes that fall within 2*diffContext lines of each other into a
+// single hunk the way `diff -u` does.
+func unifiedHunks(dl []diffLine) [][2]int {
+	var changed []int
+	for i, l := range dl {
+		if l.kind != ' ' {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	var ranges [][2]int
+	start, end := changed[0], changed[0]+1
+	for _, i := range changed[1:] {
+		if i-end <= 2*diffContext {
+			end = i + 1
+			continue
+		}
+		ranges = append(ranges, [2]int{start, end})
+		start, end = i, i+1
+	}
+	ranges = append(ranges, [2]int{start, end})
+
+	hunks := make([][2]int, len(ranges))
+	for i, rg := range ranges {
+		s, e := rg[0]-diffContext, rg[1]+diffContext
+		if s < 0 {
+			s = 0
+		}
+		if e > len(dl) {
+			e = len(dl)
+		}
+		if i > 0 && s < hunks[i-1][1] {
+			s = hunks[i-1][1]
+		}
+		hunks[i] = [2]int{s, e}
+	}
+	return hunks
+}
+
+// unifiedDiff renders a `diff -u`-style unified diff between disk (the
+// on-disk contents of path) and generated (what the Generators
+// produced): a --- /+++ header, one or more @@ -oldStart,oldCount
+// +newStart,newCount @@ hunks, and their lines prefixed with "-", "+", or
+// " " for removed, added, and unchanged lines respectively.
+func unifiedDiff(path, disk, generated string) string {
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(disk, generated)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	dl := flattenDiffLines(diffs)
+
+	bb := &bytes.Buffer{}
+	fmt.Fprintf(bb, "--- a/%s\n+++ b/%s\n", path, path)
+
+	for _, h := range unifiedHunks(dl) {
+		s, e := h[0], h[1]
+
+		var oldStart, oldCount, newStart, newCount int
+		for k := s; k < e; k++ {
+			l := dl[k]
+			if l.kind != '+' {
+				if oldStart == 0 {
+					oldStart = l.oldLine
+				}
+				oldCount++
+			}
+			if l.kind != '-' {
+				if newStart == 0 {
+					newStart = l.newLine
+				}
+				newCount++
+			}
+		}
+
+		fmt.Fprintf(bb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for k := s; k < e; k++ {
+			fmt.Fprintf(bb, "%c%s\n", dl[k].kind, dl[k].text)
+		}
+	}
+
+	return bb.String()
+}
+
+// VerifyErrors returns the aggregated mismatch error for a VerifyRunner
+// once Run has finished, or nil if every generated File matched disk.
+// Run already returns this error itself via FinalizeFn; VerifyErrors
+// remains for callers that want to inspect mismatches without treating
+// them as fatal.
+func (r *Runner) VerifyErrors() error {
+	if r.verifyMismatches == nil || len(*r.verifyMismatches) == 0 {
+		return nil
+	}
+
+	msg := "genny: generated files are out of date:\n"
+	for _, m := range *r.verifyMismatches {
+		msg += "\n" + m + "\n"
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,145 @@
+package genny
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_VerifyRunner_Run_Matches(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genny-verify")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.txt")
+	r.NoError(ioutil.WriteFile(path, []byte("hello"), 0644))
+
+	g := New()
+	g.File(NewFileS(path, "hello"))
+
+	run := VerifyRunner(context.Background())
+	run.With(g)
+
+	r.NoError(run.Run())
+	r.NoError(run.VerifyErrors())
+}
+
+func Test_VerifyRunner_Run_UsesRoot(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genny-verify")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	r.NoError(ioutil.WriteFile(filepath.Join(dir, "foo.txt"), []byte("hello"), 0644))
+
+	g := New()
+	g.File(NewFileS("foo.txt", "hello"))
+
+	run := VerifyRunner(context.Background())
+	run.Root = dir
+	run.With(g)
+
+	r.NoError(run.Run())
+	r.NoError(run.VerifyErrors())
+}
+
+func Test_VerifyRunner_Run_Mismatch(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genny-verify")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.txt")
+	r.NoError(ioutil.WriteFile(path, []byte("old"), 0644))
+
+	g := New()
+	g.File(NewFileS(path, "new"))
+
+	run := VerifyRunner(context.Background())
+	run.With(g)
+
+	err = run.Run()
+	r.Error(err)
+	r.Contains(err.Error(), path)
+	r.Contains(err.Error(), "-old")
+	r.Contains(err.Error(), "+new")
+
+	// Run's own error already is the aggregated mismatch error.
+	r.Equal(err.Error(), run.VerifyErrors().Error())
+}
+
+func Test_VerifyRunner_Run_Mismatch_BlankLineInserted(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genny-verify")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.go")
+	r.NoError(ioutil.WriteFile(path, []byte("package foo\nfunc Old() {}\n"), 0644))
+
+	g := New()
+	g.File(NewFileS(path, "package foo\n\nfunc Old() {}\n"))
+
+	run := VerifyRunner(context.Background())
+	run.With(g)
+
+	err = run.Run()
+	r.Error(err)
+	// A diff whose only change is an inserted blank line must still produce
+	// a hunk: flattenDiffLines used to trim a lone "\n" diff down to "" and
+	// skip it outright, silently dropping the insertion (and its line-number
+	// bookkeeping) from the rendered diff.
+	r.Contains(err.Error(), "@@")
+	r.Contains(err.Error(), " package foo")
+	r.Contains(err.Error(), " func Old() {}")
+}
+
+func Test_VerifyRunner_Run_MissingFromDisk(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genny-verify")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.txt")
+
+	g := New()
+	g.File(NewFileS(path, "new"))
+
+	run := VerifyRunner(context.Background())
+	run.With(g)
+
+	err = run.Run()
+	r.Error(err)
+	r.Contains(err.Error(), "missing from disk")
+}
+
+func Test_VerifyRunner_Run_NeverWritesToDisk(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genny-verify")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "foo.txt")
+
+	g := New()
+	g.File(NewFileS(path, "new"))
+
+	run := VerifyRunner(context.Background())
+	run.With(g)
+
+	run.Run()
+
+	_, statErr := os.Stat(path)
+	r.True(os.IsNotExist(statErr))
+}